@@ -0,0 +1,73 @@
+package kafka
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// clientMetricsHooks implements franz-go's broker hook interfaces so we can observe per-broker
+// request latency and throttling directly from the client, the same way we'd instrument any other
+// outbound dependency, instead of only inferring broker slowness from end-to-end test latency.
+type clientMetricsHooks struct {
+	requestLatency *prometheus.HistogramVec
+	throttleTime   *prometheus.CounterVec
+}
+
+var (
+	_ kgo.HookBrokerE2E      = (*clientMetricsHooks)(nil)
+	_ kgo.HookBrokerThrottle = (*clientMetricsHooks)(nil)
+)
+
+// newClientMetricsHooks creates the broker metric hooks. It returns nil when the feature is disabled
+// so callers can pass it straight into kgo.WithHooks without any conditional wiring, and the client
+// pays zero overhead for collecting samples nobody will scrape.
+func newClientMetricsHooks(cfg KafkaClientMetricsConfig, metricNamespace string) kgo.Hook {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	return &clientMetricsHooks{
+		requestLatency: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricNamespace,
+			Subsystem: "kafka",
+			Name:      "broker_request_latency_seconds",
+			Help:      "Latency of requests sent to a single Kafka broker, as observed by the client (write + read wait and transfer time).",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"broker_id", "api_key"}),
+		throttleTime: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: "kafka",
+			Name:      "broker_throttle_seconds_total",
+			Help:      "Cumulative throttle time brokers have asked the client to back off, as reported in the throttle_time_ms field of responses.",
+		}, []string{"broker_id"}),
+	}
+}
+
+// OnBrokerE2E implements kgo.HookBrokerE2E. It fires once per request/response round trip, after the
+// request has been fully written and the response fully read.
+func (h *clientMetricsHooks) OnBrokerE2E(meta kgo.BrokerMetadata, key int16, e2e kgo.BrokerE2E) {
+	if e2e.WriteErr != nil || e2e.ReadErr != nil {
+		return
+	}
+
+	brokerID := strconv.Itoa(int(meta.NodeID))
+	apiKey := kmsg.NameForKey(key)
+	latency := e2e.WriteWait + e2e.TimeToWrite + e2e.ReadWait + e2e.TimeToRead
+
+	h.requestLatency.WithLabelValues(brokerID, apiKey).Observe(latency.Seconds())
+}
+
+// OnBrokerThrottle implements kgo.HookBrokerThrottle, firing whenever a broker response carries a
+// non-zero throttle_time_ms.
+func (h *clientMetricsHooks) OnBrokerThrottle(meta kgo.BrokerMetadata, throttleInterval time.Duration, _ bool) {
+	if throttleInterval <= 0 {
+		return
+	}
+
+	h.throttleTime.WithLabelValues(strconv.Itoa(int(meta.NodeID))).Add(throttleInterval.Seconds())
+}
@@ -0,0 +1,16 @@
+package kafka
+
+import "github.com/twmb/franz-go/pkg/kgo"
+
+// Config configures the shared Kafka client used by the rest of kminion.
+type Config struct {
+	Brokers []string `koanf:"brokers"`
+
+	// KgoOpts lets callers plug in additional franz-go client options (TLS, SASL, ...) that aren't
+	// otherwise exposed as dedicated config fields.
+	KgoOpts []kgo.Opt `koanf:"-"`
+
+	// ClientMetrics configures the optional per-broker request latency / throttle metrics collected
+	// directly from the client via its hook interfaces.
+	ClientMetrics KafkaClientMetricsConfig `koanf:"clientMetrics"`
+}
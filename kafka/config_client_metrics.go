@@ -0,0 +1,15 @@
+package kafka
+
+// KafkaClientMetricsConfig configures the optional per-broker request latency / throttle metrics that
+// are collected directly from the franz-go client via its hook interfaces.
+type KafkaClientMetricsConfig struct {
+	// Enabled controls whether the broker_request_latency_seconds histogram and
+	// broker_throttle_seconds_total counter are collected. Disabled by default since most setups get
+	// enough signal from the end-to-end test latency and broker-side JMX metrics.
+	Enabled bool `koanf:"enabled"`
+}
+
+// SetDefaults sets the default values for the kafka client metrics config.
+func (c *KafkaClientMetricsConfig) SetDefaults() {
+	c.Enabled = false
+}
@@ -0,0 +1,13 @@
+package kafka
+
+import "github.com/twmb/franz-go/pkg/kgo"
+
+// NewDirectFetchClient returns a new, independent *kgo.Client configured with the same seed brokers,
+// TLS/SASL and other options as the service's shared client. Callers that need to issue one-off
+// direct-partition fetches (AddConsumePartitions/PollFetches) - for example to read a single record's
+// timestamp - must not do so on the shared client: PollFetches has a single logical owner (the
+// continuously-running __consumer_offsets consumer), and a second caller polling it would steal
+// records from that consumer and race its partition assignment.
+func (s *Service) NewDirectFetchClient() (*kgo.Client, error) {
+	return kgo.NewClient(s.KgoOpts...)
+}
@@ -0,0 +1,43 @@
+package kafka
+
+import (
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.uber.org/zap"
+)
+
+// Service wraps the shared franz-go client used by the rest of kminion to talk to the cluster.
+type Service struct {
+	Cfg    Config
+	logger *zap.Logger
+
+	Client *kgo.Client
+
+	// KgoOpts is retained so callers that need an independent client (see NewDirectFetchClient) can
+	// create one with the exact same brokers/authentication as the shared client.
+	KgoOpts []kgo.Opt
+}
+
+// NewService creates the shared Kafka client, wiring in the optional per-broker metrics hooks.
+func NewService(cfg Config, logger *zap.Logger, metricNamespace string) (*Service, error) {
+	opts := append([]kgo.Opt{kgo.SeedBrokers(cfg.Brokers...)}, cfg.KgoOpts...)
+
+	// newClientMetricsHooks returns nil when the feature flag is off; no hook is registered then, so
+	// the client pays nothing for the disabled metrics.
+	if hook := newClientMetricsHooks(cfg.ClientMetrics, metricNamespace); hook != nil {
+		opts = append(opts, kgo.WithHooks(hook))
+	}
+
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	}
+
+	return &Service{
+		Cfg:     cfg,
+		logger:  logger,
+		Client:  client,
+		KgoOpts: opts,
+	}, nil
+}
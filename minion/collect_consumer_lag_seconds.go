@@ -0,0 +1,168 @@
+package minion
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.uber.org/zap"
+)
+
+// getDirectFetchClient lazily creates the dedicated client used for one-off record fetches, so it's
+// never constructed unless something actually needs to resolve a record timestamp.
+func (s *Service) getDirectFetchClient() (*kgo.Client, error) {
+	s.directFetchClientOnce.Do(func() {
+		s.directFetchClient, s.directFetchClientErr = s.kafkaSvc.NewDirectFetchClient()
+	})
+
+	return s.directFetchClient, s.directFetchClientErr
+}
+
+// lagSecondsCacheTTL is how long a resolved partition backlog-seconds value is reused before it's
+// recomputed. Backlog time changes slowly compared to offset lag, so a short TTL is enough to avoid
+// refetching records on every scrape without the value going stale.
+const lagSecondsCacheTTL = 15 * time.Second
+
+func (s *Service) newConsumerGroupLagSecondsGauges(metricNamespace string) {
+	s.consumerGroupLagSeconds = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricNamespace,
+		Subsystem: "kafka",
+		Name:      "consumergroup_lag_seconds",
+		Help:      "Time difference (in seconds) between the timestamp of the last produced record and the timestamp of the consumer group's committed record, for a given topic partition.",
+	}, []string{"group_id", "topic", "partition"})
+
+	s.lagSecondsLookupFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: "kafka",
+		Name:      "lag_seconds_lookup_failures_total",
+		Help:      "Number of times kminion failed to resolve a record timestamp needed to calculate backlog seconds, e.g. because the fetch timed out.",
+	})
+}
+
+// CollectConsumerGroupLagSeconds refreshes the consumergroup_lag_seconds gauges for the given groups'
+// partitions. Unlike offset-based lag, this requires fetching individual records to read their
+// timestamps, so results are cached per partition for lagSecondsCacheTTL.
+func (s *Service) CollectConsumerGroupLagSeconds(ctx context.Context, groupID string, lags []GroupTopicPartitionLag) {
+	for _, lag := range lags {
+		seconds, err := s.getBacklogSeconds(ctx, groupID, lag.Topic, lag.Partition, lag.CommittedOffset, lag.EndOffset)
+		if err != nil {
+			s.logger.Debug("failed to calculate backlog seconds",
+				zap.String("group_id", groupID), zap.String("topic", lag.Topic), zap.Int32("partition", lag.Partition), zap.Error(err))
+			s.lagSecondsLookupFailures.Inc()
+			seconds = math.NaN()
+		}
+		s.consumerGroupLagSeconds.WithLabelValues(groupID, lag.Topic, fmt.Sprintf("%d", lag.Partition)).Set(seconds)
+	}
+}
+
+// getBacklogSeconds resolves how many seconds of wall-clock time the group is behind on a single
+// partition, by diffing the timestamp of the group's committed record against the timestamp of the
+// partition's latest record.
+func (s *Service) getBacklogSeconds(ctx context.Context, groupID, topic string, partition int32, committedOffset, endOffset int64) (float64, error) {
+	key := fmt.Sprintf("backlog-seconds-%s-%s-%d", groupID, topic, partition)
+	if cached, exists := s.getCachedItem(key); exists {
+		return cached.(float64), nil
+	}
+
+	v, err, _ := s.requestGroup.Do(key, func() (interface{}, error) {
+		seconds, err := s.calculateBacklogSeconds(ctx, topic, partition, committedOffset, endOffset)
+		if err != nil {
+			return nil, err
+		}
+		s.setCachedItem(key, seconds, lagSecondsCacheTTL)
+		return seconds, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return v.(float64), nil
+}
+
+func (s *Service) calculateBacklogSeconds(ctx context.Context, topic string, partition int32, committedOffset, endOffset int64) (float64, error) {
+	if endOffset <= 0 {
+		return 0, nil // nothing produced yet
+	}
+
+	committedTimestamp, err := s.timestampForOffset(ctx, topic, partition, committedOffset)
+	if err != nil {
+		// Compacted/empty positions may not have a record exactly at committedOffset. Fall back to
+		// the record right before it.
+		committedTimestamp, err = s.timestampForOffset(ctx, topic, partition, committedOffset-1)
+		if err != nil {
+			return 0, fmt.Errorf("failed to resolve committed record timestamp: %w", err)
+		}
+	}
+
+	latestTimestamp, err := s.timestampForOffset(ctx, topic, partition, endOffset-1)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve latest record timestamp: %w", err)
+	}
+
+	backlog := latestTimestamp.Sub(committedTimestamp).Seconds()
+	if backlog < 0 {
+		backlog = 0
+	}
+
+	return backlog, nil
+}
+
+// timestampForOffset fetches a single record at the given offset and returns its timestamp. It bounds
+// the fetch so a missing/compacted record (or an unresponsive broker) doesn't block a scrape forever.
+//
+// This deliberately does NOT use s.kafkaSvc.Client: that client's PollFetches is owned by the
+// continuously-running __consumer_offsets consumer (startConsumingOffsets). A second goroutine
+// calling AddConsumePartitions/PollFetches/RemoveConsumePartitions on it would race that consumer's
+// partition assignment and intermittently steal its records, so point-fetches like this one get their
+// own dedicated client instead.
+//
+// That dedicated client is itself shared across concurrent callers (different groups/partitions being
+// scraped at once, or offsetsTopicLag looping over several partitions), so the same
+// AddConsumePartitions/PollFetches/RemoveConsumePartitions sequence must be serialized per call -
+// otherwise two concurrent lookups would race each other's assignment and steal each other's records,
+// reintroducing the exact problem this client was split off to avoid.
+func (s *Service) timestampForOffset(ctx context.Context, topic string, partition int32, offset int64) (time.Time, error) {
+	if offset < 0 {
+		return time.Time{}, fmt.Errorf("invalid offset %d", offset)
+	}
+
+	client, err := s.getDirectFetchClient()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get direct fetch client: %w", err)
+	}
+
+	s.directFetchClientMu.Lock()
+	defer s.directFetchClientMu.Unlock()
+
+	fetchCtx, cancel := context.WithTimeout(ctx, 6*time.Second)
+	defer cancel()
+
+	client.AddConsumePartitions(map[string]map[int32]kgo.Offset{
+		topic: {partition: kgo.NewOffset().At(offset)},
+	})
+	defer client.RemoveConsumePartitions(map[string][]int32{topic: {partition}})
+
+	fetches := client.PollFetches(fetchCtx)
+	if err := fetchCtx.Err(); err != nil {
+		return time.Time{}, fmt.Errorf("timed out waiting for record at offset %d: %w", offset, err)
+	}
+
+	var result time.Time
+	found := false
+	fetches.EachRecord(func(r *kgo.Record) {
+		if found || r.Topic != topic || r.Partition != partition {
+			return
+		}
+		result = r.Timestamp
+		found = true
+	})
+	if !found {
+		return time.Time{}, fmt.Errorf("no record found at offset %d", offset)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,309 @@
+package minion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"go.uber.org/zap"
+)
+
+// GroupTopicPartitionLag is the calculated lag (in messages) for a single partition that is
+// consumed by a single consumer group.
+type GroupTopicPartitionLag struct {
+	GroupID            string
+	Topic              string
+	Partition          int32
+	CommittedOffset    int64
+	EndOffset          int64
+	Lag                int64
+	FallbackOffsetUsed bool
+}
+
+func (s *Service) newConsumerGroupLagGauges(metricNamespace string) {
+	s.consumerGroupLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricNamespace,
+		Subsystem: "kafka",
+		Name:      "consumergroup_lag",
+		Help:      "The number of messages a consumer group is behind for a given topic partition. Falls back to a synthetic offset when the group has no committed offset for that partition.",
+	}, []string{"group_id", "topic", "partition"})
+}
+
+// getConsumerGroupLags returns the lag (in messages) per allowed consumer group. It first tries kadm's
+// built-in lag calculation, which relies on the group having active members and covers the allowed
+// topics' partitions kadm reports for the group. Groups without live members (e.g. because they are
+// idle or all consumers died) report no lag via that path, so we fall back to a manual calculation
+// based on committed offsets and the configured fallback offset; that path covers every partition of
+// every allowed topic, including ones the group never committed to.
+func (s *Service) getConsumerGroupLags(ctx context.Context, groupIDs []string) (map[string][]GroupTopicPartitionLag, error) {
+	key := fmt.Sprintf("consumer-group-lags-%v", groupIDs)
+	if cached, exists := s.getCachedItem(key); exists {
+		return cached.(map[string][]GroupTopicPartitionLag), nil
+	}
+
+	v, err, _ := s.requestGroup.Do(key, func() (interface{}, error) {
+		lags, err := s.collectConsumerGroupLags(ctx, groupIDs)
+		if err != nil {
+			return nil, err
+		}
+		s.setCachedItem(key, lags, s.Cfg.ConsumerGroups.ScrapeIntervalOrDefault())
+		return lags, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(map[string][]GroupTopicPartitionLag), nil
+}
+
+// CollectConsumerGroupLags refreshes the consumergroup_lag gauges for the given groups. It's invoked
+// on each Prometheus scrape rather than on a fixed timer, relying on the cache/singleflight path to
+// keep repeated scrapes cheap.
+func (s *Service) CollectConsumerGroupLags(ctx context.Context, groupIDs []string) error {
+	lagsByGroup, err := s.getConsumerGroupLags(ctx, groupIDs)
+	if err != nil {
+		return err
+	}
+
+	for groupID, lags := range lagsByGroup {
+		for _, lag := range lags {
+			s.consumerGroupLag.WithLabelValues(lag.GroupID, lag.Topic, fmt.Sprintf("%d", lag.Partition)).Set(float64(lag.Lag))
+		}
+		s.CollectConsumerGroupLagSeconds(ctx, groupID, lags)
+	}
+
+	return nil
+}
+
+func (s *Service) collectConsumerGroupLags(ctx context.Context, groupIDs []string) (map[string][]GroupTopicPartitionLag, error) {
+	admClient := kadm.NewClient(s.kafkaSvc.Client)
+
+	described, err := admClient.DescribeGroups(ctx, groupIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe consumer groups: %w", err)
+	}
+
+	topics, err := s.listAllowedTopics(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list allowed topics: %w", err)
+	}
+
+	result := make(map[string][]GroupTopicPartitionLag, len(groupIDs))
+	for _, groupID := range groupIDs {
+		if !s.isGroupAllowed(groupID) {
+			continue
+		}
+
+		group, exists := described[groupID]
+		if exists && len(group.Members) > 0 {
+			lags, err := s.lagFromKadm(ctx, admClient, groupID, topics)
+			if err != nil {
+				s.logger.Warn("failed to calculate consumer group lag via kadm, falling back to manual calculation",
+					zap.String("group_id", groupID), zap.Error(err))
+			} else {
+				result[groupID] = lags
+				continue
+			}
+		}
+
+		// Group has no active members (or kadm's lag calculation failed) - fall back to a manual
+		// calculation so idle/rebalancing groups don't silently report zero lag.
+		lags, err := s.lagFallback(ctx, admClient, groupID, topics)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate fallback lag for group %q: %w", groupID, err)
+		}
+		result[groupID] = lags
+	}
+
+	return result, nil
+}
+
+// lagFromKadm uses kadm's group lag calculation, which only works for groups that currently have
+// active, participating members.
+func (s *Service) lagFromKadm(ctx context.Context, admClient *kadm.Client, groupID string, topics []string) ([]GroupTopicPartitionLag, error) {
+	described, err := admClient.Lag(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	groupLag, exists := described[groupID]
+	if !exists {
+		return nil, fmt.Errorf("kadm returned no lag info for group %q", groupID)
+	}
+
+	lags := make([]GroupTopicPartitionLag, 0)
+	for _, topicLags := range groupLag.Lag {
+		for _, partitionLag := range topicLags {
+			if !s.isTopicAllowed(partitionLag.Topic) {
+				continue
+			}
+			lags = append(lags, GroupTopicPartitionLag{
+				GroupID:         groupID,
+				Topic:           partitionLag.Topic,
+				Partition:       partitionLag.Partition,
+				CommittedOffset: partitionLag.Commit.At,
+				EndOffset:       partitionLag.End.Offset,
+				Lag:             partitionLag.Lag,
+			})
+		}
+	}
+
+	return lags, nil
+}
+
+// lagFallback computes lag for a group without relying on kadm's member-based calculation. For every
+// partition of every allowed topic it fetches the committed offset (if any), the partition's start and
+// end offsets, and substitutes the configured fallback offset for partitions the group never committed
+// to.
+func (s *Service) lagFallback(ctx context.Context, admClient *kadm.Client, groupID string, topics []string) ([]GroupTopicPartitionLag, error) {
+	committed, err := admClient.FetchOffsets(ctx, groupID)
+	if err != nil && !isGroupIDNotFoundErr(err) {
+		return nil, fmt.Errorf("failed to fetch committed offsets: %w", err)
+	}
+
+	startOffsets, err := admClient.ListStartOffsets(ctx, topics...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list start offsets: %w", err)
+	}
+	endOffsets, err := admClient.ListEndOffsets(ctx, topics...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list end offsets: %w", err)
+	}
+
+	lags := make([]GroupTopicPartitionLag, 0)
+	endOffsets.Each(func(o kadm.ListedOffset) {
+		if o.Err != nil {
+			return
+		}
+
+		committedOffset, hasCommit := int64(-1), false
+		if fetched, exists := committed.Lookup(o.Topic, o.Partition); exists && fetched.Err == nil {
+			committedOffset, hasCommit = fetched.At, true
+		}
+
+		fallbackUsed := false
+		if !hasCommit {
+			startOffset, _ := startOffsets.Lookup(o.Topic, o.Partition)
+			resolved, err := s.resolveFallbackOffset(ctx, o.Topic, o.Partition, startOffset.Offset, o.Offset)
+			if err != nil {
+				s.logger.Warn("failed to resolve fallback offset",
+					zap.String("group_id", groupID), zap.String("topic", o.Topic), zap.Int32("partition", o.Partition), zap.Error(err))
+				return
+			}
+			committedOffset = resolved
+			fallbackUsed = true
+		}
+
+		lag := o.Offset - committedOffset
+		if lag < 0 {
+			lag = 0
+		}
+
+		lags = append(lags, GroupTopicPartitionLag{
+			GroupID:            groupID,
+			Topic:              o.Topic,
+			Partition:          o.Partition,
+			CommittedOffset:    committedOffset,
+			EndOffset:          o.Offset,
+			Lag:                lag,
+			FallbackOffsetUsed: fallbackUsed,
+		})
+	})
+
+	return lags, nil
+}
+
+// resolveFallbackOffset turns the configured fallback offset (earliest/-2, latest/-1, or a wall-clock
+// timestamp in milliseconds) into a concrete offset for the given partition.
+func (s *Service) resolveFallbackOffset(ctx context.Context, topic string, partition int32, startOffset, endOffset int64) (int64, error) {
+	switch s.Cfg.ConsumerGroups.FallbackOffset.Offset {
+	case -2:
+		return startOffset, nil
+	case -1:
+		return endOffset, nil
+	default:
+		return s.ListOffsetsAfterMilli(ctx, topic, partition, s.Cfg.ConsumerGroups.FallbackOffset.Offset)
+	}
+}
+
+// ListOffsetsAfterMilli resolves the offset of the first record at or after the given wall-clock
+// timestamp (in milliseconds since the epoch) for a single partition.
+func (s *Service) ListOffsetsAfterMilli(ctx context.Context, topic string, partition int32, timestampMilli int64) (int64, error) {
+	admClient := kadm.NewClient(s.kafkaSvc.Client)
+
+	listed, err := admClient.ListOffsetsAfterMilli(ctx, timestampMilli, topic)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list offsets after %d for topic %q: %w", timestampMilli, topic, err)
+	}
+
+	o, exists := listed.Lookup(topic, partition)
+	if !exists {
+		return 0, fmt.Errorf("no offset found for topic %q partition %d at timestamp %d", topic, partition, timestampMilli)
+	}
+	if o.Err != nil {
+		return 0, fmt.Errorf("failed to list offset for topic %q partition %d: %w", topic, partition, o.Err)
+	}
+
+	return o.Offset, nil
+}
+
+// isGroupIDNotFoundErr reports whether err represents Kafka telling us the consumer group simply has
+// no committed offsets at all, which we treat the same as "no commit for any partition" instead of a
+// hard failure.
+func isGroupIDNotFoundErr(err error) bool {
+	return errors.Is(err, kerr.GroupIDNotFound)
+}
+
+// isTopicAllowed reports whether topic passes the configured allow/ignore filters.
+func (s *Service) isTopicAllowed(topic string) bool {
+	return isAllowed(topic, s.AllowedTopicsExpr, s.IgnoredTopicsExpr)
+}
+
+// isGroupAllowed reports whether groupID passes the configured allow/ignore filters.
+func (s *Service) isGroupAllowed(groupID string) bool {
+	return isAllowed(groupID, s.AllowedGroupIDsExpr, s.IgnoredGroupIDsExpr)
+}
+
+func isAllowed(name string, allowed, ignored []*regexp.Regexp) bool {
+	for _, expr := range ignored {
+		if expr.MatchString(name) {
+			return false
+		}
+	}
+
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, expr := range allowed {
+		if expr.MatchString(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// listAllowedTopics returns the metadata-derived list of topic names that pass the configured
+// allow/ignore filters.
+func (s *Service) listAllowedTopics(ctx context.Context) ([]string, error) {
+	admClient := kadm.NewClient(s.kafkaSvc.Client)
+
+	metadata, err := admClient.Metadata(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch metadata: %w", err)
+	}
+
+	topics := make([]string, 0, len(metadata.Topics))
+	for topic := range metadata.Topics {
+		if s.isTopicAllowed(topic) {
+			topics = append(topics, topic)
+		}
+	}
+
+	return topics, nil
+}
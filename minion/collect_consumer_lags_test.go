@@ -0,0 +1,67 @@
+package minion
+
+import (
+	"context"
+	"regexp"
+	"testing"
+)
+
+func TestResolveFallbackOffset(t *testing.T) {
+	tests := []struct {
+		name           string
+		fallbackOffset int64
+		startOffset    int64
+		endOffset      int64
+		want           int64
+	}{
+		{name: "earliest", fallbackOffset: -2, startOffset: 5, endOffset: 42, want: 5},
+		{name: "latest", fallbackOffset: -1, startOffset: 5, endOffset: 42, want: 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{}
+			s.Cfg.ConsumerGroups.FallbackOffset.Offset = tt.fallbackOffset
+
+			got, err := s.resolveFallbackOffset(context.Background(), "topic", 0, tt.startOffset, tt.endOffset)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAllowed(t *testing.T) {
+	compile := func(patterns ...string) []*regexp.Regexp {
+		exprs := make([]*regexp.Regexp, len(patterns))
+		for i, p := range patterns {
+			exprs[i] = regexp.MustCompile(p)
+		}
+		return exprs
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		allowed []*regexp.Regexp
+		ignored []*regexp.Regexp
+		want    bool
+	}{
+		{name: "no filters allows everything", input: "foo", want: true},
+		{name: "ignored takes precedence over allowed", input: "foo", allowed: compile("foo"), ignored: compile("foo"), want: false},
+		{name: "matches an allow pattern", input: "foo", allowed: compile("^foo$"), want: true},
+		{name: "doesn't match any allow pattern", input: "bar", allowed: compile("^foo$"), want: false},
+		{name: "matches an ignore pattern with no allow list", input: "bar", ignored: compile("^bar$"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isAllowed(tt.input, tt.allowed, tt.ignored); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package minion
+
+import "time"
+
+// ConsumerGroupScrapeMode determines how kminion collects consumer group offsets/lag.
+type ConsumerGroupScrapeMode string
+
+const (
+	// ConsumerGroupScrapeModeAdminAPI asks the Kafka admin API for group offsets on every scrape.
+	ConsumerGroupScrapeModeAdminAPI ConsumerGroupScrapeMode = "adminApi"
+	// ConsumerGroupScrapeModeOffsetsTopic consumes the internal __consumer_offsets topic directly and
+	// keeps an in-memory view of every group's committed offsets instead of querying on each scrape.
+	ConsumerGroupScrapeModeOffsetsTopic ConsumerGroupScrapeMode = "offsetsTopic"
+)
+
+// scrapeIntervalDefault is used whenever ConsumerGroupsConfig.ScrapeInterval isn't set.
+const scrapeIntervalDefault = 30 * time.Second
+
+// ConsumerGroupsConfig configures how kminion discovers consumer groups and calculates their lag.
+type ConsumerGroupsConfig struct {
+	Enabled bool `koanf:"enabled"`
+
+	ScrapeMode     ConsumerGroupScrapeMode `koanf:"scrapeMode"`
+	ScrapeInterval time.Duration           `koanf:"scrapeInterval"`
+
+	AllowedGroupIDs []string `koanf:"allowedGroupIds"`
+	IgnoredGroupIDs []string `koanf:"ignoredGroupIds"`
+
+	// FallbackOffset is substituted for partitions a group has no committed offset for, so idle or
+	// memberless groups don't silently report zero lag.
+	FallbackOffset FallbackOffsetConfig `koanf:"fallbackOffset"`
+
+	// StartupTargetLag is the backlog time the __consumer_offsets consumer must catch up to before
+	// kminion reports itself ready, in ConsumerGroupScrapeModeOffsetsTopic.
+	StartupTargetLag time.Duration `koanf:"startupTargetLag"`
+	// StartupMaxLag is an acceptable-but-not-ideal backlog time: if StartupMaxWait elapses and the
+	// consumer is at least within StartupMaxLag, kminion reports ready without warning.
+	StartupMaxLag time.Duration `koanf:"startupMaxLag"`
+	// StartupMaxWait bounds how long kminion will block readiness waiting for catch-up before giving
+	// up and reporting ready anyway (setting kminion_startup_lag_target_missed if it had to).
+	StartupMaxWait time.Duration `koanf:"startupMaxWait"`
+}
+
+// SetDefaults sets the default values for the consumer groups config.
+func (c *ConsumerGroupsConfig) SetDefaults() {
+	c.ScrapeMode = ConsumerGroupScrapeModeOffsetsTopic
+	c.ScrapeInterval = scrapeIntervalDefault
+	c.FallbackOffset.SetDefaults()
+
+	c.StartupTargetLag = 2 * time.Second
+	c.StartupMaxLag = 15 * time.Second
+	c.StartupMaxWait = 5 * time.Minute
+}
+
+// ScrapeIntervalOrDefault returns the configured scrape interval, falling back to
+// scrapeIntervalDefault when it hasn't been set.
+func (c *ConsumerGroupsConfig) ScrapeIntervalOrDefault() time.Duration {
+	if c.ScrapeInterval <= 0 {
+		return scrapeIntervalDefault
+	}
+	return c.ScrapeInterval
+}
+
+// FallbackOffsetConfig configures what offset to substitute when a consumer group has no committed
+// offset for a partition.
+type FallbackOffsetConfig struct {
+	// Offset is interpreted the same way Kafka's ListOffsets API interprets a timestamp request: -2
+	// resolves to the partition's earliest offset, -1 to its latest, and any other (positive) value
+	// is treated as a timestamp in milliseconds since the epoch, resolved via ListOffsetsAfterMilli.
+	Offset int64 `koanf:"offset"`
+}
+
+// SetDefaults sets the default values for the fallback offset config.
+func (c *FallbackOffsetConfig) SetDefaults() {
+	c.Offset = -1 // latest
+}
@@ -0,0 +1,31 @@
+package minion
+
+import "time"
+
+// HistogramBucketsConfig configures how the end-to-end latency histograms' buckets are generated.
+// Buckets grow geometrically from Floor up to the metric's configured SLA, landing exactly on the SLA
+// (and, when Count is greater than 2, on SLA/2) so alerting rules such as
+// histogram_quantile(0.99, ...) > sla have an exact bucket boundary to key off instead of
+// interpolating between buckets that over- or undershoot it. With Count of 2 or less the buckets are
+// just Floor and the SLA; there is no SLA/2 boundary.
+type HistogramBucketsConfig struct {
+	// Floor is the first (smallest) bucket boundary.
+	Floor time.Duration `koanf:"floor"`
+	// Count is the number of buckets to generate. Ignored if Buckets is set.
+	Count int `koanf:"count"`
+	// Buckets, if set, is used verbatim instead of generating buckets from Floor/Count/the SLA.
+	Buckets []float64 `koanf:"buckets"`
+
+	// NativeHistogramBucketFactor enables Prometheus native (sparse) histograms when set to a value
+	// greater than 1. See https://prometheus.io/docs/specs/native_histograms/ - requires Prometheus
+	// 2.40+ on the scraping side.
+	NativeHistogramBucketFactor float64 `koanf:"nativeHistogramBucketFactor"`
+	// NativeHistogramMaxBucketNumber caps how many sparse buckets a native histogram may use.
+	NativeHistogramMaxBucketNumber uint32 `koanf:"nativeHistogramMaxBucketNumber"`
+}
+
+// SetDefaults sets the default values for the histogram buckets config.
+func (c *HistogramBucketsConfig) SetDefaults() {
+	c.Floor = 5 * time.Millisecond
+	c.Count = 10
+}
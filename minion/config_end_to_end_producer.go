@@ -0,0 +1,31 @@
+package minion
+
+// EndToEndOnBufferFull controls what the end-to-end producer does when its in-flight buffer of
+// not-yet-acked messages is full.
+type EndToEndOnBufferFull string
+
+const (
+	// EndToEndOnBufferFullBlock blocks the producer loop until a buffered message is acked, applying
+	// backpressure instead of dropping test messages.
+	EndToEndOnBufferFullBlock EndToEndOnBufferFull = "block"
+	// EndToEndOnBufferFullDrop drops the message and increments end_to_end_produce_errors_total with
+	// reason "buffer_full" instead of blocking the producer loop.
+	EndToEndOnBufferFullDrop EndToEndOnBufferFull = "drop"
+)
+
+// EndToEndProducerConfig configures the end-to-end test's producer.
+type EndToEndProducerConfig struct {
+	// MaxBufferedRecords bounds how many end-to-end messages may be in flight (produced but not yet
+	// acked) at once.
+	MaxBufferedRecords int `koanf:"maxBufferedRecords"`
+
+	// OnBufferFull decides whether the producer blocks or drops a message once MaxBufferedRecords is
+	// reached.
+	OnBufferFull EndToEndOnBufferFull `koanf:"onBufferFull"`
+}
+
+// SetDefaults sets the default values for the end-to-end producer config.
+func (c *EndToEndProducerConfig) SetDefaults() {
+	c.MaxBufferedRecords = 1000
+	c.OnBufferFull = EndToEndOnBufferFullBlock
+}
@@ -0,0 +1,122 @@
+package minion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.uber.org/zap"
+)
+
+// endToEndMessage is the payload kminion produces to, and expects to receive back from, its
+// end-to-end test topic.
+type endToEndMessage struct {
+	MinionID  string `json:"minionID"`
+	Timestamp int64  `json:"timestamp"` // unix ms
+}
+
+func (s *Service) newEndToEndProducerMetrics(metricNamespace string) {
+	s.endToEndProduceErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricNamespace,
+		Subsystem: "end_to_end",
+		Name:      "produce_errors_total",
+		Help:      "Number of end-to-end test messages that failed to produce, labelled by error class.",
+	}, []string{"reason"})
+}
+
+// initEndToEnd runs the end-to-end producer loop for as long as ctx is alive. Messages are produced
+// asynchronously: the produce callback is independent of this goroutine's context, so cancelling ctx
+// (e.g. during shutdown) never aborts an in-flight produce. StopEndToEnd drains anything still
+// in-flight by calling client.Flush with a separate shutdown context.
+func (s *Service) initEndToEnd(ctx context.Context) {
+	inFlight := make(chan struct{}, s.Cfg.EndToEnd.Producer.MaxBufferedRecords)
+
+	ticker := time.NewTicker(s.Cfg.EndToEnd.ProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.produceEndToEndMessage(ctx, inFlight)
+		}
+	}
+}
+
+// produceEndToEndMessage enqueues a single end-to-end test message for asynchronous production. If the
+// in-flight buffer is already full, it either blocks until a slot frees up or drops the message,
+// depending on Cfg.EndToEnd.Producer.OnBufferFull. The blocking wait also observes ctx, so a stalled
+// broker (acks never arriving) can't wedge shutdown: without that, this runs synchronously inside
+// initEndToEnd's select loop, and a bare blocking send would never let ctx.Done() be noticed.
+func (s *Service) produceEndToEndMessage(ctx context.Context, inFlight chan struct{}) {
+	select {
+	case inFlight <- struct{}{}:
+	default:
+		if s.Cfg.EndToEnd.Producer.OnBufferFull == EndToEndOnBufferFullDrop {
+			s.endToEndProduceErrors.WithLabelValues("buffer_full").Inc()
+			return
+		}
+		select {
+		case inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+	}
+
+	msg := endToEndMessage{MinionID: s.minionID, Timestamp: time.Now().UnixMilli()}
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		<-inFlight
+		s.endToEndProduceErrors.WithLabelValues("other").Inc()
+		return
+	}
+
+	record := &kgo.Record{Topic: s.Cfg.EndToEnd.TopicManagement.Name, Value: payload}
+	s.endToEndMessagesProduced.Inc()
+	produceStart := time.Now()
+
+	// Deliberately context.Background() here, not ctx from initEndToEnd: cancelling the context that
+	// scheduled this produce must never cause an already in-flight message to be aborted. Shutdown is
+	// instead handled by draining in-flight produces via client.Flush(shutdownCtx) in StopEndToEnd.
+	s.kafkaSvc.Client.Produce(context.Background(), record, func(r *kgo.Record, err error) {
+		defer func() { <-inFlight }()
+
+		if err != nil {
+			s.countProduceError(err)
+			return
+		}
+
+		s.onAck(r.Partition, time.Since(produceStart))
+	})
+}
+
+func (s *Service) countProduceError(err error) {
+	reason := "broker"
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		reason = "context_canceled"
+	}
+
+	s.endToEndProduceErrors.WithLabelValues(reason).Inc()
+	s.logger.Debug("failed to produce end-to-end message", zap.String("reason", reason), zap.Error(err))
+}
+
+// StopEndToEnd flushes any end-to-end messages that are still in flight. The caller must pass a
+// context that still has time left once the rest of the service has been told to shut down, otherwise
+// buffered messages are abandoned rather than delivered.
+func (s *Service) StopEndToEnd(shutdownCtx context.Context) error {
+	if !s.Cfg.EndToEnd.Enabled {
+		return nil
+	}
+
+	if err := s.kafkaSvc.Client.Flush(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to flush end-to-end producer during shutdown: %w", err)
+	}
+
+	return nil
+}
@@ -6,12 +6,14 @@ import (
 	"math"
 	"regexp"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cloudhut/kminion/v2/kafka"
 	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/twmb/franz-go/pkg/kgo"
 	"github.com/twmb/franz-go/pkg/kmsg"
 	"github.com/twmb/franz-go/pkg/kversion"
 	"go.uber.org/zap"
@@ -35,6 +37,26 @@ type Service struct {
 	kafkaSvc *kafka.Service
 	storage  *Storage
 
+	// Consumer group lag
+	consumerGroupLag         *prometheus.GaugeVec
+	consumerGroupLagSeconds  *prometheus.GaugeVec
+	lagSecondsLookupFailures prometheus.Counter
+
+	// directFetchClient is a dedicated client (separate from kafkaSvc.Client) used for one-off
+	// direct-partition fetches, e.g. to read a single record's timestamp. It must stay separate from
+	// the shared client because PollFetches on that client is owned by the continuously-running
+	// __consumer_offsets consumer. directFetchClientMu serializes the
+	// AddConsumePartitions/PollFetches/RemoveConsumePartitions sequence across concurrent callers,
+	// since the client itself has no notion of "this fetch belongs to this caller".
+	directFetchClient     *kgo.Client
+	directFetchClientOnce sync.Once
+	directFetchClientErr  error
+	directFetchClientMu   sync.Mutex
+
+	// ready reports whether kminion has passed its startup catch-up gate (if any) and is safe to scrape
+	ready                  atomic.Bool
+	startupLagTargetMissed prometheus.Gauge
+
 	// EndToEnd
 	minionID               string  // unique identifier, reported in metrics, in case multiple instances run at the same time
 	lastRoundtripTimestamp float64 // creation time (in utc ms) of the message that most recently passed the roundtripSla check
@@ -48,6 +70,8 @@ type Service struct {
 	endToEndAckLatency       prometheus.Histogram
 	endToEndRoundtripLatency prometheus.Histogram
 	endToEndCommitLatency    prometheus.Histogram
+
+	endToEndProduceErrors *prometheus.CounterVec
 }
 
 func NewService(cfg Config, logger *zap.Logger, kafkaSvc *kafka.Service, metricNamespace string) (*Service, error) {
@@ -83,6 +107,14 @@ func NewService(cfg Config, logger *zap.Logger, kafkaSvc *kafka.Service, metricN
 		lastRoundtripTimestamp: 0,
 	}
 
+	service.ready.Store(true)
+
+	if cfg.ConsumerGroups.Enabled {
+		service.newConsumerGroupLagGauges(metricNamespace)
+		service.newConsumerGroupLagSecondsGauges(metricNamespace)
+		service.newStartupGateMetrics(metricNamespace)
+	}
+
 	// End-to-End metrics
 	if cfg.EndToEnd.Enabled {
 		makeCounter := func(name string, help string) prometheus.Counter {
@@ -94,12 +126,15 @@ func NewService(cfg Config, logger *zap.Logger, kafkaSvc *kafka.Service, metricN
 			})
 		}
 		makeHistogram := func(name string, maxLatency time.Duration, help string) prometheus.Histogram {
+			bucketsCfg := cfg.EndToEnd.HistogramBuckets
 			return promauto.NewHistogram(prometheus.HistogramOpts{
-				Namespace: metricNamespace,
-				Subsystem: "end_to_end",
-				Name:      name,
-				Help:      help,
-				Buckets:   createHistogramBuckets(maxLatency),
+				Namespace:                      metricNamespace,
+				Subsystem:                      "end_to_end",
+				Name:                           name,
+				Help:                           help,
+				Buckets:                        createHistogramBuckets(maxLatency, bucketsCfg),
+				NativeHistogramBucketFactor:    bucketsCfg.NativeHistogramBucketFactor,
+				NativeHistogramMaxBucketNumber: bucketsCfg.NativeHistogramMaxBucketNumber,
 			})
 		}
 
@@ -116,6 +151,8 @@ func NewService(cfg Config, logger *zap.Logger, kafkaSvc *kafka.Service, metricN
 		service.endToEndAckLatency = makeHistogram("produce_latency_seconds", cfg.EndToEnd.Producer.AckSla, "Time until we received an ack for a produced message")
 		service.endToEndRoundtripLatency = makeHistogram("roundtrip_latency_seconds", cfg.EndToEnd.Consumer.RoundtripSla, "Time it took between sending (producing) and receiving (consuming) a message")
 		service.endToEndCommitLatency = makeHistogram("commit_latency_seconds", cfg.EndToEnd.Consumer.CommitSla, "Time kafka took to respond to kminion's offset commit")
+
+		service.newEndToEndProducerMetrics(metricNamespace)
 	}
 
 	return service, nil
@@ -127,8 +164,10 @@ func (s *Service) Start(ctx context.Context) error {
 		return fmt.Errorf("failed to check feature compatibility against Kafka: %w", err)
 	}
 
-	if s.Cfg.ConsumerGroups.ScrapeMode == ConsumerGroupScrapeModeOffsetsTopic {
+	if s.Cfg.ConsumerGroups.Enabled && s.Cfg.ConsumerGroups.ScrapeMode == ConsumerGroupScrapeModeOffsetsTopic {
+		s.setReady(false)
 		go s.startConsumingOffsets(ctx)
+		go s.awaitConsumerGroupCatchup(ctx)
 	}
 
 	if s.Cfg.EndToEnd.Enabled {
@@ -188,30 +227,57 @@ func (s *Service) deleteCachedItem(key string) {
 	delete(s.cache, key)
 }
 
-// create histogram buckets for metrics reported by 'end-to-end'
-// todo:
-/*
-- custom, much simpler, exponential buckets
-  we know:
-  	- we want to go from 5ms to 'max'
-	- we want to double each time
-	- doubling 5ms might not get us to 'max' exactly
-  questions:
-	- can we slightly adjust the factor so we hit 'max' exactly?
-	- or can we adjust 'max'?
-		(and if so, better to overshoot or undershoot?)
-	- or should we just set the last bucket to 'max' exactly?
-*/
-func createHistogramBuckets(maxLatency time.Duration) []float64 {
-	// Since this is an exponential bucket we need to take Log base2 or binary as the upper bound
-	// Divide by 10 for the argument because the base is counted as 20ms and we want to normalize it as base 2 instead of 20
-	// +2 because it starts at 5ms or 0.005 sec, to account 5ms and 10ms before it goes to the base which in this case is 0.02 sec or 20ms
-	// and another +1 to account for decimal points on int parsing
-	latencyCount := math.Logb(float64(maxLatency.Milliseconds() / 10))
-	count := int(latencyCount) + 3
-	bucket := prometheus.ExponentialBuckets(0.005, 2, count)
-
-	return bucket
+// createHistogramBuckets builds the bucket boundaries for an end-to-end latency histogram. Buckets
+// grow geometrically from cfg.Floor and land exactly on sla (with an exact boundary at sla/2 too when
+// there are more than 2 buckets; a Count below 2 is raised to 2), so
+// alerting rules like histogram_quantile(0.99, ...) > sla are meaningful instead of being thrown off
+// by buckets that over- or undershoot the configured SLA.
+func createHistogramBuckets(sla time.Duration, cfg HistogramBucketsConfig) []float64 {
+	if len(cfg.Buckets) > 0 {
+		return cfg.Buckets
+	}
+
+	count := cfg.Count
+	if count < 2 {
+		count = 2
+	}
+
+	buckets := geometricBuckets(cfg.Floor.Seconds(), sla.Seconds(), count)
+
+	// Force whichever interior bucket lands closest to sla/2 to be exactly sla/2, so alerting rules
+	// like histogram_quantile(0.99, ...) > sla have an exact boundary to key off there too, not just
+	// at sla itself. Indices 0 and len-1 are excluded from the search: they're the floor and sla
+	// boundaries callers rely on being exact, and with count==2 there is no interior bucket to force.
+	if len(buckets) > 2 {
+		half := sla.Seconds() / 2
+		closest := 1
+		for i := 2; i < len(buckets)-1; i++ {
+			if math.Abs(buckets[i]-half) < math.Abs(buckets[closest]-half) {
+				closest = i
+			}
+		}
+		buckets[closest] = half
+	}
+
+	return buckets
+}
+
+// geometricBuckets returns exactly n geometrically spaced values from start to end, inclusive on both
+// ends, with the growth factor computed as (end/start)^(1/(n-1)) so the series lands on end exactly
+// rather than approximately. For n < 2 it returns the single value end.
+func geometricBuckets(start, end float64, n int) []float64 {
+	if n < 2 {
+		return []float64{end}
+	}
+
+	factor := math.Pow(end/start, 1/float64(n-1))
+	buckets := make([]float64, n)
+	for i := 0; i < n; i++ {
+		buckets[i] = start * math.Pow(factor, float64(i))
+	}
+	buckets[n-1] = end // avoid floating point drift on a boundary callers rely on being exact
+
+	return buckets
 }
 
 // called from e2e when a message is acknowledged
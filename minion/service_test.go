@@ -0,0 +1,92 @@
+package minion
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestGeometricBuckets(t *testing.T) {
+	tests := []struct {
+		name  string
+		start float64
+		end   float64
+		n     int
+	}{
+		{name: "typical", start: 0.005, end: 1, n: 10},
+		{name: "two buckets", start: 0.005, end: 1, n: 2},
+		{name: "single bucket falls back to end", start: 0.005, end: 1, n: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets := geometricBuckets(tt.start, tt.end, tt.n)
+
+			wantLen := tt.n
+			if tt.n < 2 {
+				wantLen = 1
+			}
+			if len(buckets) != wantLen {
+				t.Fatalf("got %d buckets, want %d", len(buckets), wantLen)
+			}
+
+			if buckets[len(buckets)-1] != tt.end {
+				t.Errorf("last bucket = %v, want exactly %v", buckets[len(buckets)-1], tt.end)
+			}
+
+			for i := 1; i < len(buckets); i++ {
+				if buckets[i] <= buckets[i-1] {
+					t.Errorf("buckets not strictly increasing at index %d: %v <= %v", i, buckets[i], buckets[i-1])
+				}
+			}
+		})
+	}
+}
+
+func TestCreateHistogramBuckets(t *testing.T) {
+	t.Run("explicit buckets are returned verbatim", func(t *testing.T) {
+		cfg := HistogramBucketsConfig{Buckets: []float64{0.1, 0.2, 0.3}}
+		buckets := createHistogramBuckets(time.Second, cfg)
+		if len(buckets) != 3 || buckets[2] != 0.3 {
+			t.Fatalf("got %v, want [0.1 0.2 0.3]", buckets)
+		}
+	})
+
+	t.Run("generated buckets land exactly on the sla", func(t *testing.T) {
+		cfg := HistogramBucketsConfig{Floor: 5 * time.Millisecond, Count: 10}
+		buckets := createHistogramBuckets(time.Second, cfg)
+
+		if len(buckets) != 10 {
+			t.Fatalf("got %d buckets, want 10", len(buckets))
+		}
+		if buckets[len(buckets)-1] != 1 {
+			t.Errorf("last bucket = %v, want exactly 1", buckets[len(buckets)-1])
+		}
+
+		half := 0.5
+		foundHalf := false
+		for _, b := range buckets {
+			if b == half {
+				foundHalf = true
+			}
+		}
+		if !foundHalf {
+			t.Errorf("no bucket is exactly sla/2 (%v): %v", half, buckets)
+		}
+	})
+
+	t.Run("count=2 keeps the floor intact instead of overwriting it with sla/2", func(t *testing.T) {
+		cfg := HistogramBucketsConfig{Floor: 5 * time.Millisecond, Count: 2}
+		buckets := createHistogramBuckets(time.Second, cfg)
+
+		want := []float64{0.005, 1}
+		if len(buckets) != len(want) {
+			t.Fatalf("got %v, want %v", buckets, want)
+		}
+		for i := range want {
+			if math.Abs(buckets[i]-want[i]) > 1e-9 {
+				t.Errorf("bucket %d = %v, want %v", i, buckets[i], want[i])
+			}
+		}
+	})
+}
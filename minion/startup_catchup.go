@@ -0,0 +1,143 @@
+package minion
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"go.uber.org/zap"
+)
+
+func (s *Service) newStartupGateMetrics(metricNamespace string) {
+	s.startupLagTargetMissed = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: metricNamespace,
+		Subsystem: "startup",
+		Name:      "lag_target_missed",
+		Help:      "Set to 1 if the startup catch-up gate gave up waiting for consumer lag to drop below StartupMaxLag before StartupMaxWait elapsed.",
+	})
+}
+
+// IsReady reports whether kminion is ready to be scraped. It's false while the startup catch-up gate
+// is still waiting for the __consumer_offsets consumer to replay recent history.
+func (s *Service) IsReady() bool {
+	return s.ready.Load()
+}
+
+func (s *Service) setReady(ready bool) {
+	s.ready.Store(ready)
+}
+
+// HandleReadyHTTP is the handler for the "/ready" endpoint: it responds 200 OK once IsReady is true,
+// and 503 Service Unavailable while kminion is still waiting on its startup catch-up gate. Register it
+// on the HTTP server that also serves "/metrics".
+func (s *Service) HandleReadyHTTP(w http.ResponseWriter, _ *http.Request) {
+	if !s.IsReady() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("not ready: waiting for consumer offsets catch-up"))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("ok"))
+}
+
+// awaitConsumerGroupCatchup blocks (logging progress every 10s) until the __consumer_offsets consumer
+// has caught up to within Cfg.ConsumerGroups.StartupTargetLag, or until StartupMaxWait elapses -
+// whichever comes first. StartupMaxLag is only checked once StartupMaxWait has elapsed, to decide
+// whether kminion_startup_lag_target_missed is set. This keeps Prometheus from scraping
+// incomplete consumer-group offset data right after a restart or rebalance, while still guaranteeing
+// kminion eventually becomes ready even if the offsets topic is unusually large.
+func (s *Service) awaitConsumerGroupCatchup(ctx context.Context) {
+	cfg := s.Cfg.ConsumerGroups
+	deadline := time.Now().Add(cfg.StartupMaxWait)
+
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		lag, assigned, err := s.offsetsTopicLag(ctx)
+		switch {
+		case err != nil:
+			s.logger.Warn("failed to measure startup catch-up lag, will retry", zap.Error(err))
+		case !assigned:
+			// No offsets-topic partitions have been assigned to our internal consumer yet (it may
+			// still be joining/rebalancing), so there's nothing to measure lag against. This must NOT
+			// be treated as "caught up" - an empty MarkedOffsets() looks identical to a lag of zero.
+			s.logger.Info("waiting for consumer offsets partitions to be assigned")
+		case lag <= cfg.StartupTargetLag:
+			s.logger.Info("consumer offsets caught up, marking kminion ready", zap.Duration("lag", lag))
+			s.setReady(true)
+			return
+		default:
+			s.logger.Info("waiting for consumer offsets to catch up", zap.Duration("lag", lag), zap.Duration("target_lag", cfg.StartupTargetLag))
+		}
+
+		if time.Now().After(deadline) {
+			if err == nil && assigned && lag <= cfg.StartupMaxLag {
+				s.logger.Info("consumer offsets catch-up within acceptable bounds, marking kminion ready", zap.Duration("lag", lag))
+			} else {
+				s.logger.Warn("giving up waiting for consumer offsets catch-up, marking kminion ready anyway",
+					zap.Duration("lag", lag), zap.Duration("max_wait", cfg.StartupMaxWait))
+				s.startupLagTargetMissed.Set(1)
+			}
+			s.setReady(true)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// offsetsTopicLag returns the largest backlog time across the partitions of the offsets topic that
+// kminion's internal consumer currently has assigned. assigned is false when MarkedOffsets() is empty
+// (e.g. the consumer hasn't joined/rebalanced yet), which callers must treat differently from "caught
+// up" even though both report a zero time.Duration.
+func (s *Service) offsetsTopicLag(ctx context.Context) (lag time.Duration, assigned bool, err error) {
+	client := s.kafkaSvc.Client
+	marked := client.MarkedOffsets()
+	admClient := kadm.NewClient(client)
+
+	var maxLag time.Duration
+	for topic, partitions := range marked {
+		if len(partitions) == 0 {
+			continue
+		}
+
+		ends, err := admClient.ListEndOffsets(ctx, topic)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to list end offsets for %q: %w", topic, err)
+		}
+
+		for partition, committed := range partitions {
+			assigned = true
+
+			end, exists := ends.Lookup(topic, partition)
+			if !exists || end.Offset == 0 {
+				continue
+			}
+
+			latestTs, err := s.timestampForOffset(ctx, topic, partition, end.Offset-1)
+			if err != nil {
+				continue
+			}
+			currentTs, err := s.timestampForOffset(ctx, topic, partition, committed.Offset)
+			if err != nil {
+				continue
+			}
+
+			if d := latestTs.Sub(currentTs); d > maxLag {
+				maxLag = d
+			}
+		}
+	}
+
+	return maxLag, assigned, nil
+}